@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"framefold/pkg/framefold"
 )
@@ -14,16 +17,20 @@ func main() {
 		configPath   string
 		sourceDir    string
 		targetDir    string
+		outputPath   string
 		deleteSource bool
 		showVersion  bool
+		dedup        bool
 	)
 
 	// Parse command line flags
 	flag.StringVar(&configPath, "config", "", "Path to configuration file (optional)")
 	flag.StringVar(&sourceDir, "source", "", "Source directory containing photos")
 	flag.StringVar(&targetDir, "target", "", "Target directory to organize photos")
+	flag.StringVar(&outputPath, "output", "", "Path to write the list of processed files to (optional)")
 	flag.BoolVar(&deleteSource, "delete-source", false, "Delete source files after successful copy (default: false)")
 	flag.BoolVar(&showVersion, "version", false, "Show version information")
+	flag.BoolVar(&dedup, "dedup", false, "Store files in a content-addressed layout and hardlink them into the folder template (default: false)")
 	flag.Parse()
 
 	// Show version if requested
@@ -42,14 +49,21 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	if dedup {
+		config.ContentAddressed.Enabled = true
+	}
 
 	// Create and run processor
-	processor, err := framefold.NewProcessor(sourceDir, targetDir, config, deleteSource)
+	processor, err := framefold.NewProcessor(sourceDir, targetDir, config, deleteSource, outputPath)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if err := processor.Process(); err != nil {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := processor.ProcessContext(ctx); err != nil {
+		fmt.Println(processor.GetStats().String()) // stats gathered before the error or cancellation
 		log.Fatal(err)
 	}
 
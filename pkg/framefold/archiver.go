@@ -0,0 +1,78 @@
+package framefold
+
+import (
+	"fmt"
+	"os"
+)
+
+// Archiver decides what happens when a new file collides with a different
+// one already occupying its destination path -- e.g. two cameras both
+// producing IMG_0001.jpg on the same day. The hook shape is inspired by
+// log-rotation archivers: ShouldArchiveNow gates whether the existing file
+// is moved aside at all, NextLogFilePath says where, and the Hook methods
+// let a strategy run custom notifications or post-processing around that
+// move.
+type Archiver interface {
+	// ShouldArchiveNow reports whether the file already at destPath should
+	// be archived before the incoming file is written.
+	ShouldArchiveNow(destPath string, existing os.FileInfo) bool
+
+	// NextLogFilePath returns the path the existing file at destPath
+	// should be moved to in order to archive it.
+	NextLogFilePath(destPath string) (string, error)
+
+	// HookBeforeArchive and HookAfterArchive run immediately before/after
+	// the existing file is moved to archivePath.
+	HookBeforeArchive(destPath, archivePath string) error
+	HookAfterArchive(destPath, archivePath string) error
+
+	// IncomingPath returns the path the incoming file should actually be
+	// written to once any archiving above has been resolved. It's usually
+	// destPath unchanged; the "suffix" strategy returns a decorated
+	// sibling instead, and "skip" returns "" to mean the incoming file
+	// should be dropped entirely.
+	IncomingPath(destPath string) (string, error)
+}
+
+// newArchiver builds the Archiver selected by cfg.Strategy. targetDir is
+// the root the "trash" strategy anchors its .framefold-trash directory to.
+func newArchiver(cfg ArchiverConfig, targetDir string) (Archiver, error) {
+	switch cfg.Strategy {
+	case "", "overwrite":
+		return overwriteArchiver{}, nil
+	case "skip":
+		return skipArchiver{}, nil
+	case "suffix":
+		return suffixArchiver{}, nil
+	case "trash":
+		return trashArchiver{targetDir: targetDir}, nil
+	case "staggered":
+		keep := cfg.KeepVersions
+		if keep < 1 {
+			keep = 1
+		}
+		return staggeredArchiver{keepVersions: keep}, nil
+	default:
+		return nil, fmt.Errorf("unknown archiver strategy %q", cfg.Strategy)
+	}
+}
+
+// overwriteArchiver is the original behavior: the incoming file replaces
+// whatever is at destPath, and nothing is archived.
+type overwriteArchiver struct{}
+
+func (overwriteArchiver) ShouldArchiveNow(destPath string, existing os.FileInfo) bool { return false }
+func (overwriteArchiver) NextLogFilePath(destPath string) (string, error)              { return "", nil }
+func (overwriteArchiver) HookBeforeArchive(destPath, archivePath string) error         { return nil }
+func (overwriteArchiver) HookAfterArchive(destPath, archivePath string) error          { return nil }
+func (overwriteArchiver) IncomingPath(destPath string) (string, error)                 { return destPath, nil }
+
+// skipArchiver leaves whatever already exists at destPath untouched and
+// drops the incoming file.
+type skipArchiver struct{}
+
+func (skipArchiver) ShouldArchiveNow(destPath string, existing os.FileInfo) bool { return false }
+func (skipArchiver) NextLogFilePath(destPath string) (string, error)             { return "", nil }
+func (skipArchiver) HookBeforeArchive(destPath, archivePath string) error        { return nil }
+func (skipArchiver) HookAfterArchive(destPath, archivePath string) error         { return nil }
+func (skipArchiver) IncomingPath(destPath string) (string, error)                { return "", nil }
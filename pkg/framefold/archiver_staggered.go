@@ -0,0 +1,84 @@
+package framefold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// staggeredVersionsDirName is the sibling directory the "staggered"
+// archiver strategy keeps superseded versions in.
+const staggeredVersionsDirName = "Versions"
+
+// staggeredStampRE matches the stamp NextLogFilePath inserts between a
+// version's base name and its extension. Requiring the full shape (rather
+// than a bare prefix match) keeps HookAfterArchive from mistaking one
+// file's versions for another's, e.g. "photo-2-<stamp>.jpg" for a version
+// of "photo.jpg" just because it also starts with "photo-".
+var staggeredStampRE = regexp.MustCompile(`^\d{8}-\d{6}\.\d{9}$`)
+
+// staggeredArchiver keeps the keepVersions most recent versions of a file
+// in a sibling Versions/ folder, pruning older ones after each archive.
+type staggeredArchiver struct {
+	keepVersions int
+}
+
+func (staggeredArchiver) ShouldArchiveNow(destPath string, existing os.FileInfo) bool { return true }
+
+func (staggeredArchiver) NextLogFilePath(destPath string) (string, error) {
+	dir := filepath.Dir(destPath)
+	ext := filepath.Ext(destPath)
+	base := strings.TrimSuffix(filepath.Base(destPath), ext)
+	stamp := time.Now().Format("20060102-150405.000000000")
+	return filepath.Join(dir, staggeredVersionsDirName, fmt.Sprintf("%s-%s%s", base, stamp, ext)), nil
+}
+
+func (staggeredArchiver) HookBeforeArchive(destPath, archivePath string) error { return nil }
+
+// HookAfterArchive prunes the Versions/ sibling down to keepVersions
+// entries for this file, removing the oldest ones first.
+func (a staggeredArchiver) HookAfterArchive(destPath, archivePath string) error {
+	versionsDir := filepath.Dir(archivePath)
+	ext := filepath.Ext(destPath)
+	base := strings.TrimSuffix(filepath.Base(destPath), ext)
+	prefix := base + "-"
+
+	entries, err := os.ReadDir(versionsDir)
+	if err != nil {
+		return fmt.Errorf("error reading versions directory %s: %v", versionsDir, err)
+	}
+
+	var versions []os.DirEntry
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) || !strings.HasSuffix(entry.Name(), ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(entry.Name(), prefix), ext)
+		if !staggeredStampRE.MatchString(stamp) {
+			continue
+		}
+		versions = append(versions, entry)
+	}
+
+	if len(versions) <= a.keepVersions {
+		return nil
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Name() < versions[j].Name() // timestamped names sort chronologically
+	})
+
+	for _, old := range versions[:len(versions)-a.keepVersions] {
+		if err := os.Remove(filepath.Join(versionsDir, old.Name())); err != nil {
+			return fmt.Errorf("error pruning old version %s: %v", old.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+func (staggeredArchiver) IncomingPath(destPath string) (string, error) { return destPath, nil }
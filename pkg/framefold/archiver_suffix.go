@@ -0,0 +1,32 @@
+package framefold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// suffixArchiver never touches the existing file; instead it finds the
+// next free "name-1.ext", "name-2.ext", ... sibling for the incoming file.
+type suffixArchiver struct{}
+
+func (suffixArchiver) ShouldArchiveNow(destPath string, existing os.FileInfo) bool { return false }
+func (suffixArchiver) NextLogFilePath(destPath string) (string, error)             { return "", nil }
+func (suffixArchiver) HookBeforeArchive(destPath, archivePath string) error        { return nil }
+func (suffixArchiver) HookAfterArchive(destPath, archivePath string) error         { return nil }
+
+func (suffixArchiver) IncomingPath(destPath string) (string, error) {
+	dir := filepath.Dir(destPath)
+	ext := filepath.Ext(destPath)
+	base := strings.TrimSuffix(filepath.Base(destPath), ext)
+
+	for n := 1; ; n++ {
+		candidate := filepath.Join(dir, fmt.Sprintf("%s-%d%s", base, n, ext))
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package framefold
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewArchiverUnknownStrategy(t *testing.T) {
+	if _, err := newArchiver(ArchiverConfig{Strategy: "bogus"}, t.TempDir()); err == nil {
+		t.Fatal("expected an error for an unknown archiver strategy")
+	}
+}
+
+func TestSkipArchiverDropsIncoming(t *testing.T) {
+	a := skipArchiver{}
+	incoming, err := a.IncomingPath("/dest/photo.jpg")
+	if err != nil {
+		t.Fatalf("IncomingPath: %v", err)
+	}
+	if incoming != "" {
+		t.Errorf("skip archiver should return an empty incoming path, got %q", incoming)
+	}
+}
+
+func TestOverwriteArchiverLeavesDestUntouched(t *testing.T) {
+	a := overwriteArchiver{}
+	if a.ShouldArchiveNow("/dest/photo.jpg", nil) {
+		t.Error("overwrite archiver should never archive the existing file")
+	}
+	incoming, err := a.IncomingPath("/dest/photo.jpg")
+	if err != nil {
+		t.Fatalf("IncomingPath: %v", err)
+	}
+	if incoming != "/dest/photo.jpg" {
+		t.Errorf("overwrite archiver should write to the original path, got %q", incoming)
+	}
+}
+
+func TestSuffixArchiverFindsNextFreeName(t *testing.T) {
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "photo.jpg")
+	if err := os.WriteFile(dest, []byte("existing"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "photo-1.jpg"), []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := suffixArchiver{}
+	incoming, err := a.IncomingPath(dest)
+	if err != nil {
+		t.Fatalf("IncomingPath: %v", err)
+	}
+	want := filepath.Join(dir, "photo-2.jpg")
+	if incoming != want {
+		t.Errorf("IncomingPath() = %q, want %q", incoming, want)
+	}
+}
+
+func TestTrashArchiverMovesExistingFileAside(t *testing.T) {
+	targetDir := t.TempDir()
+	dest := filepath.Join(targetDir, "photo.jpg")
+	if err := os.WriteFile(dest, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := trashArchiver{targetDir: targetDir}
+	if !a.ShouldArchiveNow(dest, nil) {
+		t.Fatal("trash archiver should always archive the existing file")
+	}
+
+	archivePath, err := a.NextLogFilePath(dest)
+	if err != nil {
+		t.Fatalf("NextLogFilePath: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(dest, archivePath); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(archivePath, filepath.Join(targetDir, trashDirName)) {
+		t.Errorf("archive path %q should be under %s", archivePath, trashDirName)
+	}
+	if _, err := os.Stat(archivePath); err != nil {
+		t.Errorf("archived file not found at %s: %v", archivePath, err)
+	}
+
+	incoming, err := a.IncomingPath(dest)
+	if err != nil {
+		t.Fatalf("IncomingPath: %v", err)
+	}
+	if incoming != dest {
+		t.Errorf("trash archiver should write the incoming file back to dest, got %q", incoming)
+	}
+}
+
+func TestStaggeredArchiverPrunesOldVersions(t *testing.T) {
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "photo.jpg")
+	versionsDir := filepath.Join(dir, staggeredVersionsDirName)
+	if err := os.MkdirAll(versionsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Seed three existing versions, oldest first by name.
+	names := []string{
+		"photo-20240101-000000.000000000.jpg",
+		"photo-20240102-000000.000000000.jpg",
+		"photo-20240103-000000.000000000.jpg",
+	}
+	for _, n := range names {
+		if err := os.WriteFile(filepath.Join(versionsDir, n), []byte(n), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := staggeredArchiver{keepVersions: 2}
+	archivePath := filepath.Join(versionsDir, "photo-20240104-000000.000000000.jpg")
+	if err := a.HookAfterArchive(dest, archivePath); err != nil {
+		t.Fatalf("HookAfterArchive: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(versionsDir, names[0])); !os.IsNotExist(err) {
+		t.Errorf("oldest version should have been pruned, stat err = %v", err)
+	}
+	for _, n := range names[1:] {
+		if _, err := os.Stat(filepath.Join(versionsDir, n)); err != nil {
+			t.Errorf("version %s should still exist: %v", n, err)
+		}
+	}
+}
+
+// TestStaggeredArchiverPruningDoesNotMatchAnotherFilesVersions reproduces
+// the ambiguity where pruning "photo.jpg" versions also matched
+// "photo-2.jpg" versions, since both share the "photo-" prefix.
+func TestStaggeredArchiverPruningDoesNotMatchAnotherFilesVersions(t *testing.T) {
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "photo.jpg")
+	versionsDir := filepath.Join(dir, staggeredVersionsDirName)
+	if err := os.MkdirAll(versionsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	otherVersions := []string{
+		"photo-2-20240101-000000.000000000.jpg",
+		"photo-2-20240102-000000.000000000.jpg",
+		"photo-2-20240103-000000.000000000.jpg",
+	}
+	for _, n := range otherVersions {
+		if err := os.WriteFile(filepath.Join(versionsDir, n), []byte(n), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldPhotoVersion := "photo-20240101-000000.000000000.jpg"
+	if err := os.WriteFile(filepath.Join(versionsDir, oldPhotoVersion), []byte(oldPhotoVersion), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := staggeredArchiver{keepVersions: 2}
+	archivePath := filepath.Join(versionsDir, "photo-20240104-000000.000000000.jpg")
+	if err := a.HookAfterArchive(dest, archivePath); err != nil {
+		t.Fatalf("HookAfterArchive: %v", err)
+	}
+
+	for _, n := range otherVersions {
+		if _, err := os.Stat(filepath.Join(versionsDir, n)); err != nil {
+			t.Errorf("photo-2.jpg version %s should not have been touched: %v", n, err)
+		}
+	}
+}
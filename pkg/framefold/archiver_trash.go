@@ -0,0 +1,30 @@
+package framefold
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// trashDirName is the top-level directory under the target that the
+// "trash" archiver strategy moves superseded files into.
+const trashDirName = ".framefold-trash"
+
+// trashArchiver moves the existing file into a timestamped
+// .framefold-trash/<timestamp>/ directory, under targetDir, before the
+// incoming file takes its place.
+type trashArchiver struct {
+	targetDir string
+}
+
+func (trashArchiver) ShouldArchiveNow(destPath string, existing os.FileInfo) bool { return true }
+
+func (a trashArchiver) NextLogFilePath(destPath string) (string, error) {
+	run := time.Now().Format("20060102-150405.000000000")
+	return filepath.Join(a.targetDir, trashDirName, run, filepath.Base(destPath)), nil
+}
+
+func (trashArchiver) HookBeforeArchive(destPath, archivePath string) error { return nil }
+func (trashArchiver) HookAfterArchive(destPath, archivePath string) error  { return nil }
+
+func (trashArchiver) IncomingPath(destPath string) (string, error) { return destPath, nil }
@@ -4,14 +4,38 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"runtime"
+	"time"
 )
 
 // Config holds the application configuration
 type Config struct {
-	FolderTemplate  string              `json:"folder_template"`
-	MediaTypes      map[string][]string `json:"media_types"`
-	UseOriginalName bool                `json:"use_original_filename"`
-	Logging         LoggingConfig       `json:"logging"`
+	FolderTemplate   string                 `json:"folder_template"`
+	MediaTypes       map[string][]string    `json:"media_types"`
+	UseOriginalName  bool                   `json:"use_original_filename"`
+	Logging          LoggingConfig          `json:"logging"`
+	ContentAddressed ContentAddressedConfig `json:"content_addressed"`
+	Concurrency      ConcurrencyConfig      `json:"concurrency"`
+	Archiver         ArchiverConfig         `json:"archiver"`
+	// Timeout bounds the entire run (0 means no timeout). It's honored by
+	// ProcessContext via context.WithTimeout, in addition to whatever
+	// cancellation the caller's ctx already carries.
+	Timeout time.Duration `json:"timeout"`
+	// PreserveTimes makes copyFile carry the source file's access and
+	// modification times over to the copy via os.Chtimes.
+	PreserveTimes bool `json:"preserve_times"`
+	// PreserveOwnership makes copyFile carry the source file's uid/gid over
+	// to the copy via os.Chown. This only has an effect on Linux, where the
+	// uid/gid are read from the source's Stat_t; on other platforms,
+	// including other Unix systems, ownership is left unchanged. Changing
+	// ownership to another user needs root, and a failed os.Chown makes
+	// copyFile return an error.
+	PreserveOwnership bool `json:"preserve_ownership"`
+	// FollowSymlinks controls whether symlinks encountered while walking
+	// the source tree are followed (and their target processed) or left
+	// alone. Default is false: symlinks are skipped, since filepath.Walk
+	// itself already doesn't follow them (it reports them via os.Lstat).
+	FollowSymlinks bool `json:"follow_symlinks"`
 }
 
 // LoggingConfig holds logging-related configuration
@@ -20,6 +44,34 @@ type LoggingConfig struct {
 	Level   string `json:"level"`
 }
 
+// ContentAddressedConfig controls the content-addressed dedup store.
+// When enabled, every file is copied exactly once to a sharded
+// `content/<hh>/<sha256><ext>` path under the target directory, and the
+// human-facing FolderTemplate path is created as a hardlink (falling back
+// to a symlink) to that canonical copy.
+type ContentAddressedConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// ConcurrencyConfig sizes the Source -> Parse -> Copy processing pipeline.
+// ParseWorkers bounds how many exiftool subprocesses can run at once;
+// CopyWorkers bounds concurrent disk I/O independently of that, since the
+// two are typically bottlenecked by different resources.
+type ConcurrencyConfig struct {
+	ParseWorkers int `json:"parse_workers"`
+	CopyWorkers  int `json:"copy_workers"`
+	BufferSize   int `json:"buffer_size"`
+}
+
+// ArchiverConfig selects how name collisions (same destination path,
+// different content) are resolved. Strategy is one of "overwrite"
+// (default), "skip", "suffix", "trash", or "staggered". KeepVersions is
+// only used by "staggered".
+type ArchiverConfig struct {
+	Strategy     string `json:"strategy"`
+	KeepVersions int    `json:"keep_versions"`
+}
+
 // DefaultConfig provides default configuration values
 var DefaultConfig = Config{
 	FolderTemplate: "{{.Year}}/{{.Month}}",
@@ -32,6 +84,20 @@ var DefaultConfig = Config{
 		Enabled: true,
 		Level:   "info",
 	},
+	ContentAddressed: ContentAddressedConfig{
+		Enabled: false,
+	},
+	Concurrency: ConcurrencyConfig{
+		ParseWorkers: runtime.NumCPU(),
+		CopyWorkers:  runtime.NumCPU(),
+		BufferSize:   100,
+	},
+	Archiver: ArchiverConfig{
+		Strategy:     "overwrite",
+		KeepVersions: 5,
+	},
+	PreserveTimes:  true,
+	FollowSymlinks: false,
 }
 
 // LoadConfig loads configuration from a file, falling back to defaults if no file is specified
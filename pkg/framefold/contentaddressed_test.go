@@ -0,0 +1,172 @@
+package framefold
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func newTestProcessor(t *testing.T, config Config) *Processor {
+	t.Helper()
+	p, err := NewProcessor(t.TempDir(), t.TempDir(), config, false, "")
+	if err != nil {
+		t.Fatalf("NewProcessor: %v", err)
+	}
+	return p
+}
+
+func TestStoreContentAddressedDedupesIdenticalContent(t *testing.T) {
+	config := DefaultConfig
+	config.ContentAddressed.Enabled = true
+	p := newTestProcessor(t, config)
+
+	srcDir := t.TempDir()
+	srcA := filepath.Join(srcDir, "a.jpg")
+	srcB := filepath.Join(srcDir, "b.jpg")
+	if err := os.WriteFile(srcA, []byte("same bytes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(srcB, []byte("same bytes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	hash, err := p.calculateFileHash(ctx, srcA)
+	if err != nil {
+		t.Fatalf("calculateFileHash: %v", err)
+	}
+
+	linkA := filepath.Join(p.targetDir, "2024", "01", "a.jpg")
+	linkB := filepath.Join(p.targetDir, "2024", "01", "b.jpg")
+	if err := os.MkdirAll(filepath.Dir(linkA), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.storeContentAddressed(ctx, srcA, linkA, hash, ".jpg", 10); err != nil {
+		t.Fatalf("storeContentAddressed (first): %v", err)
+	}
+	if err := p.storeContentAddressed(ctx, srcB, linkB, hash, ".jpg", 10); err != nil {
+		t.Fatalf("storeContentAddressed (second): %v", err)
+	}
+
+	if got := p.stats.DuplicatesSkipped.Load(); got != 1 {
+		t.Errorf("DuplicatesSkipped = %d, want 1", got)
+	}
+	if got := p.stats.BytesDeduplicated.Load(); got != 10 {
+		t.Errorf("BytesDeduplicated = %d, want 10", got)
+	}
+
+	contentPath := p.contentFilePath(hash, ".jpg")
+	if _, err := os.Stat(contentPath); err != nil {
+		t.Fatalf("canonical copy missing at %s: %v", contentPath, err)
+	}
+
+	for _, link := range []string{linkA, linkB} {
+		info, err := os.Lstat(link)
+		if err != nil {
+			t.Fatalf("%s: %v", link, err)
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			if !os.SameFile(info, mustStat(t, contentPath)) {
+				t.Errorf("%s is not the same file as the canonical copy", link)
+			}
+		}
+	}
+}
+
+func mustStat(t *testing.T, path string) os.FileInfo {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+// TestStoreContentAddressedConcurrentWritersDontCorruptCanonicalCopy
+// reproduces the race where several copy workers hash to the same content
+// and race to create the canonical copy: without a lock around the
+// check-and-create, a second worker could observe contentPath mid-write
+// (or race os.Create with the first writer) and link to a corrupt or
+// incomplete file.
+func TestStoreContentAddressedConcurrentWritersDontCorruptCanonicalCopy(t *testing.T) {
+	config := DefaultConfig
+	config.ContentAddressed.Enabled = true
+	p := newTestProcessor(t, config)
+
+	content := bytes.Repeat([]byte("abcdefgh"), 128*1024) // large enough for writes to overlap
+	srcDir := t.TempDir()
+
+	const writers = 8
+	srcPaths := make([]string, writers)
+	for i := range srcPaths {
+		srcPaths[i] = filepath.Join(srcDir, fmt.Sprintf("src-%d.jpg", i))
+		if err := os.WriteFile(srcPaths[i], content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	hash, err := p.calculateFileHash(ctx, srcPaths[0])
+	if err != nil {
+		t.Fatalf("calculateFileHash: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	wg.Add(writers)
+	for i := 0; i < writers; i++ {
+		i := i
+		go func() {
+			defer wg.Done()
+			dst := filepath.Join(p.targetDir, fmt.Sprintf("out-%d.jpg", i))
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				errs[i] = err
+				return
+			}
+			errs[i] = p.storeContentAddressed(ctx, srcPaths[i], dst, hash, ".jpg", int64(len(content)))
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("storeContentAddressed writer %d: %v", i, err)
+		}
+	}
+
+	got, err := os.ReadFile(p.contentFilePath(hash, ".jpg"))
+	if err != nil {
+		t.Fatalf("reading canonical copy: %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("canonical copy is corrupt: got %d bytes, want %d matching the source", len(got), len(content))
+	}
+}
+
+func TestAreFilesIdenticalShortCircuitsOnHardlink(t *testing.T) {
+	p := newTestProcessor(t, DefaultConfig)
+	p.config.ContentAddressed.Enabled = true
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "canonical")
+	dst := filepath.Join(dir, "linked")
+	if err := os.WriteFile(src, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(src, dst); err != nil {
+		t.Skipf("hardlinks not supported on this filesystem: %v", err)
+	}
+
+	identical, err := p.areFilesIdentical(context.Background(), src, dst)
+	if err != nil {
+		t.Fatalf("areFilesIdentical: %v", err)
+	}
+	if !identical {
+		t.Error("hardlinked files should be reported identical")
+	}
+}
@@ -0,0 +1,139 @@
+package framefold
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCopyFilePreservesTimesWhenEnabled(t *testing.T) {
+	config := DefaultConfig
+	config.PreserveTimes = true
+	p := newTestProcessor(t, config)
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.jpg")
+	dst := filepath.Join(dir, "dst.jpg")
+	if err := os.WriteFile(src, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Date(2010, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(src, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.copyFile(context.Background(), src, dst); err != nil {
+		t.Fatalf("copyFile: %v", err)
+	}
+
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(old) {
+		t.Errorf("dst mtime = %v, want %v", info.ModTime(), old)
+	}
+}
+
+func TestCopyFileLeavesTimesAloneWhenDisabled(t *testing.T) {
+	config := DefaultConfig
+	config.PreserveTimes = false
+	p := newTestProcessor(t, config)
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.jpg")
+	dst := filepath.Join(dir, "dst.jpg")
+	if err := os.WriteFile(src, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Date(2010, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(src, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now()
+	if err := p.copyFile(context.Background(), src, dst); err != nil {
+		t.Fatalf("copyFile: %v", err)
+	}
+
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ModTime().Equal(old) {
+		t.Error("dst mtime should not match the source's mtime when PreserveTimes is disabled")
+	}
+	if info.ModTime().Before(before.Add(-time.Minute)) {
+		t.Errorf("dst mtime = %v, expected it to be close to the copy time", info.ModTime())
+	}
+}
+
+func TestSourceSkipsSymlinksByDefault(t *testing.T) {
+	sourceDir := t.TempDir()
+	targetDir := t.TempDir()
+
+	real := filepath.Join(sourceDir, "real.jpg")
+	if err := os.WriteFile(real, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(real, filepath.Join(sourceDir, "link.jpg")); err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultConfig
+	config.FollowSymlinks = false
+	p, err := NewProcessor(sourceDir, targetDir, config, false, "")
+	if err != nil {
+		t.Fatalf("NewProcessor: %v", err)
+	}
+
+	var walkErr error
+	paths := p.source(context.Background(), 10, &walkErr)
+	var seen []string
+	for entry := range paths {
+		seen = append(seen, entry.path)
+	}
+	if walkErr != nil {
+		t.Fatalf("walk error: %v", walkErr)
+	}
+	if len(seen) != 1 || seen[0] != real {
+		t.Errorf("source() yielded %v, want only %s", seen, real)
+	}
+}
+
+func TestSourceSkipsDanglingSymlinkWithoutAbortingWalk(t *testing.T) {
+	sourceDir := t.TempDir()
+	targetDir := t.TempDir()
+
+	good := filepath.Join(sourceDir, "good.jpg")
+	if err := os.WriteFile(good, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(sourceDir, "does-not-exist.jpg"), filepath.Join(sourceDir, "broken.jpg")); err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultConfig
+	config.FollowSymlinks = true
+	p, err := NewProcessor(sourceDir, targetDir, config, false, "")
+	if err != nil {
+		t.Fatalf("NewProcessor: %v", err)
+	}
+
+	var walkErr error
+	paths := p.source(context.Background(), 10, &walkErr)
+	var seen []string
+	for entry := range paths {
+		seen = append(seen, entry.path)
+	}
+	if walkErr != nil {
+		t.Fatalf("a dangling symlink should not abort the walk, got: %v", walkErr)
+	}
+	if len(seen) != 1 || seen[0] != good {
+		t.Errorf("source() yielded %v, want only %s", seen, good)
+	}
+}
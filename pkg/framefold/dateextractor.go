@@ -0,0 +1,57 @@
+package framefold
+
+import (
+	"context"
+	"time"
+)
+
+// DateExtractor resolves the capture date for a media file. getFileDate
+// picks an implementation per file extension via the Processor's
+// dateExtractors registry, so fast in-process readers (goexif, the mp4
+// parser) can be tried before falling back to an exiftool subprocess.
+type DateExtractor interface {
+	// ExtractDate returns the best-known capture date for the file at path.
+	// It should return ctx.Err() promptly once ctx is done.
+	ExtractDate(ctx context.Context, path string) (time.Time, error)
+}
+
+// chainExtractor tries each DateExtractor in order and returns the first
+// successful result. It's how a fast native reader and the exiftool
+// fallback are composed for a single extension.
+type chainExtractor struct {
+	extractors []DateExtractor
+}
+
+func (c chainExtractor) ExtractDate(ctx context.Context, path string) (time.Time, error) {
+	var lastErr error
+	for _, extractor := range c.extractors {
+		if err := ctx.Err(); err != nil {
+			return time.Time{}, err
+		}
+		date, err := extractor.ExtractDate(ctx, path)
+		if err == nil {
+			return date, nil
+		}
+		lastErr = err
+	}
+	return time.Time{}, lastErr
+}
+
+// defaultDateExtractors builds the per-extension DateExtractor registry:
+// goexif for JPEG/TIFF and the mp4 atom reader for .mp4/.mov, each backed
+// by the shared exiftool extractor for files the native reader can't
+// handle. Extensions with no entry here (e.g. .heic) fall back to
+// Processor.fallbackExtractor.
+func defaultDateExtractors(exiftool DateExtractor) map[string]DateExtractor {
+	jpeg := chainExtractor{extractors: []DateExtractor{goexifExtractor{}, exiftool}}
+	mp4 := chainExtractor{extractors: []DateExtractor{mp4Extractor{}, exiftool}}
+
+	return map[string]DateExtractor{
+		".jpg":  jpeg,
+		".jpeg": jpeg,
+		".tif":  jpeg,
+		".tiff": jpeg,
+		".mp4":  mp4,
+		".mov":  mp4,
+	}
+}
@@ -0,0 +1,136 @@
+package framefold
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeExtractor is a DateExtractor test double that returns a fixed result
+// and records whether it was invoked.
+type fakeExtractor struct {
+	called bool
+	date   time.Time
+	err    error
+}
+
+func (f *fakeExtractor) ExtractDate(ctx context.Context, path string) (time.Time, error) {
+	f.called = true
+	return f.date, f.err
+}
+
+func TestChainExtractorReturnsFirstSuccess(t *testing.T) {
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := &fakeExtractor{err: errors.New("no exif here")}
+	second := &fakeExtractor{date: want}
+	c := chainExtractor{extractors: []DateExtractor{first, second}}
+
+	got, err := c.ExtractDate(context.Background(), "irrelevant.jpg")
+	if err != nil {
+		t.Fatalf("ExtractDate: %v", err)
+	}
+	if !got.Equal(want) {
+		t.Errorf("ExtractDate() = %v, want %v", got, want)
+	}
+	if !first.called || !second.called {
+		t.Error("expected both extractors to be tried after the first one failed")
+	}
+}
+
+func TestChainExtractorStopsAtCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	never := &fakeExtractor{date: time.Now()}
+	c := chainExtractor{extractors: []DateExtractor{never}}
+
+	if _, err := c.ExtractDate(ctx, "irrelevant.jpg"); err == nil {
+		t.Fatal("expected ExtractDate to report the canceled context")
+	}
+	if never.called {
+		t.Error("extractor should not have been called once the context was already canceled")
+	}
+}
+
+func TestDefaultDateExtractorsRouting(t *testing.T) {
+	exiftool := &fakeExtractor{}
+	registry := defaultDateExtractors(exiftool)
+
+	for _, ext := range []string{".jpg", ".jpeg", ".tif", ".tiff", ".mp4", ".mov"} {
+		if _, ok := registry[ext]; !ok {
+			t.Errorf("expected a DateExtractor registered for %s", ext)
+		}
+	}
+	if _, ok := registry[".heic"]; ok {
+		t.Error(".heic has no dedicated reader and should fall back to Processor.fallbackExtractor instead of being in the registry")
+	}
+}
+
+// writeMinimalMP4 builds a tiny valid MP4/QuickTime container consisting of
+// an ftyp box followed by a moov box containing a version-0 mvhd box whose
+// creation time is secs seconds after the MP4 epoch.
+func writeMinimalMP4(t *testing.T, path string, secs uint32) {
+	t.Helper()
+
+	var buf []byte
+
+	appendBox := func(boxType string, payload []byte) {
+		size := make([]byte, 4)
+		binary.BigEndian.PutUint32(size, uint32(8+len(payload)))
+		buf = append(buf, size...)
+		buf = append(buf, []byte(boxType)...)
+		buf = append(buf, payload...)
+	}
+
+	appendBox("ftyp", []byte("isom"))
+
+	mvhd := make([]byte, 4+4+4) // version+flags, creation time, modification time
+	binary.BigEndian.PutUint32(mvhd[4:8], secs)
+
+	var moovPayload []byte
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(8+len(mvhd)))
+	moovPayload = append(moovPayload, size...)
+	moovPayload = append(moovPayload, []byte("mvhd")...)
+	moovPayload = append(moovPayload, mvhd...)
+
+	appendBox("moov", moovPayload)
+
+	if err := os.WriteFile(path, buf, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMP4ExtractorReadsCreationTime(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "clip.mp4")
+
+	const secsAfterEpoch = 3_661 // 1904-01-01 01:01:01
+	writeMinimalMP4(t, path, secsAfterEpoch)
+
+	got, err := (mp4Extractor{}).ExtractDate(context.Background(), path)
+	if err != nil {
+		t.Fatalf("ExtractDate: %v", err)
+	}
+
+	want := mp4Epoch.Add(secsAfterEpoch * time.Second)
+	if !got.Equal(want) {
+		t.Errorf("ExtractDate() = %v, want %v", got, want)
+	}
+}
+
+func TestMP4ExtractorMissingMoovBox(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "no-moov.mp4")
+	if err := os.WriteFile(path, []byte("\x00\x00\x00\x08ftyp"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := (mp4Extractor{}).ExtractDate(context.Background(), path); err == nil {
+		t.Fatal("expected an error when the moov box is missing")
+	}
+}
@@ -0,0 +1,39 @@
+package framefold
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rwcarlsen/goexif/exif"
+)
+
+// goexifExtractor reads DateTimeOriginal directly from a JPEG/TIFF EXIF
+// header in-process, without spawning exiftool. It only needs the header
+// bytes, so it's substantially cheaper than a subprocess per file.
+type goexifExtractor struct{}
+
+func (goexifExtractor) ExtractDate(ctx context.Context, path string) (time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return time.Time{}, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+
+	x, err := exif.Decode(f)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("goexif: %v", err)
+	}
+
+	date, err := x.DateTime()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("goexif: no DateTimeOriginal: %v", err)
+	}
+
+	return date, nil
+}
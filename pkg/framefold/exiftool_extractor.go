@@ -0,0 +1,53 @@
+package framefold
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// exiftoolExtractor shells out to exiftool for its DateTimeOriginal (or
+// DateTime) tag. It's the universal fallback used for formats goexif and
+// the mp4 reader can't handle, such as HEIC.
+type exiftoolExtractor struct {
+	checkOnce sync.Once
+	checkErr  error
+}
+
+func (e *exiftoolExtractor) ExtractDate(ctx context.Context, path string) (time.Time, error) {
+	// Check exiftool availability once, however many callers share this extractor.
+	e.checkOnce.Do(func() {
+		e.checkErr = checkExiftool()
+	})
+	if e.checkErr != nil {
+		return time.Time{}, e.checkErr
+	}
+
+	// Try to get DateTimeOriginal first, then DateTime as fallback
+	cmd := exec.CommandContext(ctx, "exiftool", "-DateTimeOriginal", "-DateTime", "-s", "-s", "-s", path)
+	output, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("exiftool execution failed: %v", err)
+	}
+
+	// Parse the output - exiftool returns the first matching tag
+	dateTimeStr := strings.TrimSpace(string(output))
+	if dateTimeStr == "" {
+		return time.Time{}, fmt.Errorf("no DateTime found in EXIF")
+	}
+
+	// Parse the EXIF date format: "YYYY:MM:DD HH:MM:SS"
+	return time.Parse("2006:01:02 15:04:05", dateTimeStr)
+}
+
+// checkExiftool verifies that exiftool is available on the system
+func checkExiftool() error {
+	cmd := exec.Command("exiftool", "-ver")
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("exiftool is not available: %v\nPlease install exiftool to extract EXIF data from media files", err)
+	}
+	return nil
+}
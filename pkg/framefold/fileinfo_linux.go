@@ -0,0 +1,29 @@
+//go:build linux
+
+package framefold
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileTimes returns the source file's access and modification times, read
+// from the platform-specific Stat_t so atime survives the copy even though
+// os.FileInfo only exposes ModTime.
+func fileTimes(info os.FileInfo) (atime, mtime time.Time) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.ModTime(), info.ModTime()
+	}
+	return time.Unix(stat.Atim.Sec, stat.Atim.Nsec), time.Unix(stat.Mtim.Sec, stat.Mtim.Nsec)
+}
+
+// fileOwner returns the source file's uid/gid.
+func fileOwner(info os.FileInfo) (uid, gid int, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(stat.Uid), int(stat.Gid), true
+}
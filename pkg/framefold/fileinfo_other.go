@@ -0,0 +1,20 @@
+//go:build !linux
+
+package framefold
+
+import (
+	"os"
+	"time"
+)
+
+// fileTimes falls back to ModTime for both atime and mtime on platforms
+// where we don't have a Stat_t-based accessor.
+func fileTimes(info os.FileInfo) (atime, mtime time.Time) {
+	return info.ModTime(), info.ModTime()
+}
+
+// fileOwner reports that ownership information isn't available on this
+// platform.
+func fileOwner(info os.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
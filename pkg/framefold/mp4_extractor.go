@@ -0,0 +1,103 @@
+package framefold
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// mp4Epoch is the MP4/QuickTime epoch (1904-01-01 UTC); mvhd creation
+// times are seconds measured from this instant.
+var mp4Epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// mp4Extractor reads the creation time directly out of an MP4/QuickTime
+// file's moov/mvhd atom, avoiding an exiftool subprocess for the common
+// .mp4/.mov case.
+type mp4Extractor struct{}
+
+func (mp4Extractor) ExtractDate(ctx context.Context, path string) (time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return time.Time{}, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	moovOffset, moovSize, err := findMP4Box(f, "moov", 0, info.Size())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("mp4: %v", err)
+	}
+
+	mvhdOffset, _, err := findMP4Box(f, "mvhd", moovOffset, moovOffset+moovSize)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("mp4: %v", err)
+	}
+
+	if _, err := f.Seek(mvhdOffset, io.SeekStart); err != nil {
+		return time.Time{}, err
+	}
+
+	var versionAndFlags [4]byte
+	if _, err := io.ReadFull(f, versionAndFlags[:]); err != nil {
+		return time.Time{}, fmt.Errorf("mp4: reading mvhd header: %v", err)
+	}
+
+	var creationTime uint64
+	if versionAndFlags[0] == 1 {
+		// Version 1: 64-bit creation/modification times.
+		var buf [8]byte
+		if _, err := io.ReadFull(f, buf[:]); err != nil {
+			return time.Time{}, fmt.Errorf("mp4: reading mvhd creation time: %v", err)
+		}
+		creationTime = binary.BigEndian.Uint64(buf[:])
+	} else {
+		// Version 0: 32-bit creation/modification times.
+		var buf [4]byte
+		if _, err := io.ReadFull(f, buf[:]); err != nil {
+			return time.Time{}, fmt.Errorf("mp4: reading mvhd creation time: %v", err)
+		}
+		creationTime = uint64(binary.BigEndian.Uint32(buf[:]))
+	}
+
+	return mp4Epoch.Add(time.Duration(creationTime) * time.Second), nil
+}
+
+// findMP4Box scans sibling boxes in [start, end) of r for one of the given
+// type, returning the offset and size of its payload (i.e. just past the
+// 8-byte size+type header). It does not recurse into child boxes.
+func findMP4Box(r io.ReadSeeker, boxType string, start, end int64) (payloadOffset, payloadSize int64, err error) {
+	pos := start
+	var header [8]byte
+	for pos < end {
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return 0, 0, err
+		}
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return 0, 0, err
+		}
+
+		size := int64(binary.BigEndian.Uint32(header[0:4]))
+		if size < 8 {
+			return 0, 0, fmt.Errorf("invalid box size in %q", string(header[4:8]))
+		}
+
+		if string(header[4:8]) == boxType {
+			return pos + 8, size - 8, nil
+		}
+
+		pos += size
+	}
+
+	return 0, 0, fmt.Errorf("%q box not found", boxType)
+}
@@ -0,0 +1,146 @@
+package framefold
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyedMutexSerializesSameKey(t *testing.T) {
+	var km keyedMutex
+	var counter int
+	var wg sync.WaitGroup
+
+	const n = 50
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			unlock := km.Lock("same-key")
+			defer unlock()
+			// A non-atomic read-modify-write: only safe because Lock
+			// serializes every caller using the same key.
+			cur := counter
+			counter = cur + 1
+		}()
+	}
+	wg.Wait()
+
+	if counter != n {
+		t.Errorf("counter = %d, want %d (keyedMutex did not serialize same-key callers)", counter, n)
+	}
+}
+
+func TestKeyedMutexAllowsDifferentKeysConcurrently(t *testing.T) {
+	var km keyedMutex
+
+	release1 := km.Lock("one")
+	done := make(chan struct{})
+	go func() {
+		release2 := km.Lock("two")
+		release2()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("locking a different key blocked on an unrelated key's lock")
+	}
+	release1()
+}
+
+// TestProcessContextConcurrentCollisionsDontClobber reproduces the race
+// where many copy workers resolve the same colliding destination path at
+// once: every source file here hashes to the exact same destination name
+// (fixed mtime, no EXIF, same media type), so the "suffix" archiver must
+// hand out a distinct "-N" suffix to each one without two workers picking
+// the same candidate.
+func TestProcessContextConcurrentCollisionsDontClobber(t *testing.T) {
+	sourceDir := t.TempDir()
+	targetDir := t.TempDir()
+
+	const fileCount = 40
+	fixedModTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < fileCount; i++ {
+		path := filepath.Join(sourceDir, fmt.Sprintf("src-%02d.jpg", i))
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("content-%d", i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, fixedModTime, fixedModTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := DefaultConfig
+	config.UseOriginalName = false
+	config.Archiver.Strategy = "suffix"
+	config.Concurrency.ParseWorkers = 8
+	config.Concurrency.CopyWorkers = 8
+
+	p, err := NewProcessor(sourceDir, targetDir, config, false, "")
+	if err != nil {
+		t.Fatalf("NewProcessor: %v", err)
+	}
+
+	if err := p.ProcessContext(context.Background()); err != nil {
+		t.Fatalf("ProcessContext: %v", err)
+	}
+
+	got := countFiles(t, targetDir)
+	if got != fileCount {
+		t.Errorf("found %d files under %s, want %d (a collision silently clobbered a file)", got, targetDir, fileCount)
+	}
+}
+
+// TestProcessContextFlushesOnCancellation checks that a canceled run still
+// reaches WriteProcessedFiles before reporting the cancellation. A
+// pre-canceled context stops the source walk at its very first callback,
+// so no file reaches a worker and the cancellation arrives as walkErr.
+func TestProcessContextFlushesOnCancellation(t *testing.T) {
+	sourceDir := t.TempDir()
+	targetDir := t.TempDir()
+	outputPath := filepath.Join(t.TempDir(), "processed.txt")
+
+	if err := os.WriteFile(filepath.Join(sourceDir, "a.jpg"), []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewProcessor(sourceDir, targetDir, DefaultConfig, false, outputPath)
+	if err != nil {
+		t.Fatalf("NewProcessor: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := p.ProcessContext(ctx); err == nil {
+		t.Fatal("expected ProcessContext to report the cancellation")
+	}
+
+	if _, err := os.Stat(outputPath); err != nil {
+		t.Errorf("expected the processed-files list to still be flushed on cancellation, but %s: %v", outputPath, err)
+	}
+}
+
+func countFiles(t *testing.T, dir string) int {
+	t.Helper()
+	count := 0
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
@@ -1,15 +1,17 @@
 package framefold
 
 import (
+	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 )
@@ -17,6 +19,11 @@ import (
 const (
 	// Buffer size for file operations (1MB)
 	copyBufferSize = 1024 * 1024
+
+	// contentDirName is the top-level directory under the target that
+	// holds the content-addressed store, sharded by the first byte of
+	// each file's SHA-256 hash (content/00 .. content/ff).
+	contentDirName = "content"
 )
 
 // FileInfo holds template variables for folder organization
@@ -28,6 +35,28 @@ type FileInfo struct {
 	Minute    string
 	MediaType string
 	Extension string
+	// Hash is the file's SHA-256 hex digest. It is only populated when
+	// Config.ContentAddressed is enabled, since computing it otherwise
+	// would cost a full read of every file for no benefit.
+	Hash string
+}
+
+// walkEntry is a single regular file discovered by source, handed to a
+// parse worker for EXIF/media-type/target-path resolution.
+type walkEntry struct {
+	path string
+	info os.FileInfo
+}
+
+// job is a file that has been walked and parsed (EXIF date, media type,
+// target path resolved) and is ready for a copy worker to place it in the
+// target directory.
+type job struct {
+	srcPath  string
+	srcSize  int64
+	destPath string
+	ext      string
+	hash     string // only set when Config.ContentAddressed is enabled
 }
 
 // Processor handles the file organization process
@@ -39,10 +68,40 @@ type Processor struct {
 	deleteSource      bool
 	processedDirs     map[string]bool // Track directories that had files processed
 	processedFiles    []string        // Track processed files for output
+	filesMu           sync.Mutex      // Guards processedDirs and processedFiles across workers
 	outputPath        string          // Path to output file
 	lock              *processLock
-	exiftoolChecked   bool // Track if exiftool availability has been checked
-	exiftoolAvailable bool // Whether exiftool is available
+	dateExtractors    map[string]DateExtractor // Per-extension EXIF/metadata readers
+	fallbackExtractor DateExtractor            // Used for extensions with no dedicated reader
+	archiver          Archiver                 // Resolves destination-path collisions
+	destLocks         keyedMutex               // Serializes collision resolution per destination path
+	contentShardsDone bool                     // Track if content-addressed shard dirs have been created
+	contentShardsMu   sync.Mutex
+}
+
+// keyedMutex hands out a per-key lock, so unrelated keys don't contend but
+// two copy workers racing on the same key (e.g. resolving the same
+// colliding destination path) are serialized.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// Lock acquires the lock for key and returns a function to release it.
+func (k *keyedMutex) Lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*sync.Mutex)
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		k.locks[key] = l
+	}
+	k.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
 }
 
 // NewProcessor creates a new file processor
@@ -52,20 +111,29 @@ func NewProcessor(sourceDir, targetDir string, config Config, deleteSource bool,
 		return nil, fmt.Errorf("failed to create process lock: %v", err)
 	}
 
+	archiver, err := newArchiver(config.Archiver, targetDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure archiver: %v", err)
+	}
+
+	exiftool := &exiftoolExtractor{}
+
 	return &Processor{
-		config:         config,
-		sourceDir:      sourceDir,
-		targetDir:      targetDir,
-		deleteSource:   deleteSource,
-		stats:          Stats{StartTime: time.Now()},
-		processedDirs:  make(map[string]bool),
-		processedFiles: make([]string, 0),
-		outputPath:     outputPath,
-		lock:           lock,
+		config:            config,
+		sourceDir:         sourceDir,
+		targetDir:         targetDir,
+		deleteSource:      deleteSource,
+		stats:             Stats{StartTime: time.Now()},
+		processedDirs:     make(map[string]bool),
+		processedFiles:    make([]string, 0),
+		outputPath:        outputPath,
+		lock:              lock,
+		dateExtractors:    defaultDateExtractors(exiftool),
+		fallbackExtractor: exiftool,
+		archiver:          archiver,
 	}, nil
 }
 
-// Process organizes files from source to target directory
 // WriteProcessedFiles writes the list of processed files to the output file
 func (p *Processor) WriteProcessedFiles() error {
 	if p.outputPath == "" {
@@ -92,7 +160,31 @@ func (p *Processor) WriteProcessedFiles() error {
 	return nil
 }
 
+// Process organizes files from source to target directory. It is
+// equivalent to ProcessContext(context.Background()).
 func (p *Processor) Process() error {
+	return p.ProcessContext(context.Background())
+}
+
+// ProcessContext organizes files from source to target directory using a
+// concurrent Source -> Parse -> Copy pipeline: a single goroutine walks
+// the source tree, a pool of parse workers resolves each candidate file's
+// EXIF date and target path, and a pool of copy workers performs the I/O.
+// Parse and copy are sized independently (Config.Concurrency) since
+// parsing is bound by exiftool subprocess spawns while copying is bound by
+// disk throughput.
+//
+// Canceling ctx (or letting Config.Timeout elapse) aborts in-flight hash
+// and copy operations promptly. Either way, ProcessContext still releases
+// the lock and flushes the processed-files list gathered so far before
+// returning, and the stats gathered so far stay readable via GetStats.
+func (p *Processor) ProcessContext(ctx context.Context) error {
+	if p.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.config.Timeout)
+		defer cancel()
+	}
+
 	// Try to acquire lock
 	locked, err := p.lock.acquire()
 	if err != nil {
@@ -108,10 +200,69 @@ func (p *Processor) Process() error {
 		return fmt.Errorf("failed to create target directory: %v", err)
 	}
 
-	// Walk through the source directory
-	err = filepath.Walk(p.sourceDir, p.processFile)
-	if err != nil {
-		return fmt.Errorf("error walking through directory: %v", err)
+	parseWorkers := p.config.Concurrency.ParseWorkers
+	if parseWorkers < 1 {
+		parseWorkers = 1
+	}
+	copyWorkers := p.config.Concurrency.CopyWorkers
+	if copyWorkers < 1 {
+		copyWorkers = 1
+	}
+	bufferSize := p.config.Concurrency.BufferSize
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+
+	var walkErr error
+	paths := p.source(ctx, bufferSize, &walkErr)
+
+	jobs := make(chan job, bufferSize)
+	errs := make(chan error, bufferSize)
+
+	var parseWG sync.WaitGroup
+	parseWG.Add(parseWorkers)
+	for i := 0; i < parseWorkers; i++ {
+		go func() {
+			defer parseWG.Done()
+			p.parseWorker(ctx, paths, jobs, errs)
+		}()
+	}
+	go func() {
+		parseWG.Wait()
+		close(jobs)
+	}()
+
+	var copyWG sync.WaitGroup
+	copyWG.Add(copyWorkers)
+	for i := 0; i < copyWorkers; i++ {
+		go func() {
+			defer copyWG.Done()
+			p.copyWorker(ctx, jobs, errs)
+		}()
+	}
+	go func() {
+		copyWG.Wait()
+		close(errs)
+	}()
+
+	var firstErr error
+	for err := range errs {
+		if firstErr == nil {
+			firstErr = err
+		} else if p.config.Logging.Enabled {
+			log.Printf("Error: %v", err)
+		}
+	}
+	// Once ctx is done, a worker's error is ctx.Err() itself or a copy/hash
+	// failure wrapping it, which is the normal, expected shape of a
+	// cancellation rather than a failure — treat it like walkErr below and
+	// fall through to the flush instead of returning early; the
+	// cancellation is reported after the flush.
+	if firstErr != nil && ctx.Err() == nil && !errors.Is(firstErr, context.Canceled) && !errors.Is(firstErr, context.DeadlineExceeded) {
+		return firstErr
+	}
+	if walkErr != nil && !errors.Is(walkErr, context.Canceled) && !errors.Is(walkErr, context.DeadlineExceeded) {
+		return fmt.Errorf("error walking through directory: %v", walkErr)
 	}
 
 	// If deleting source files, clean up empty directories
@@ -126,9 +277,71 @@ func (p *Processor) Process() error {
 		return fmt.Errorf("error writing processed files list: %v", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("processing canceled: %v", err)
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+
 	return nil
 }
 
+// source walks the source directory in its own goroutine and emits every
+// regular file it finds on the returned channel, stopping as soon as ctx
+// is done. *walkErr is set before the channel is closed, so it is safe to
+// read once the channel drains.
+func (p *Processor) source(ctx context.Context, bufferSize int, walkErr *error) <-chan walkEntry {
+	out := make(chan walkEntry, bufferSize)
+
+	go func() {
+		defer close(out)
+
+		*walkErr = filepath.Walk(p.sourceDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if info.Mode()&os.ModeSymlink != 0 {
+				if !p.config.FollowSymlinks {
+					return nil
+				}
+				// Resolve the symlink and process whatever it points at in
+				// its place; we don't recurse into symlinked directories to
+				// avoid walking into a cycle. A dangling symlink shouldn't
+				// abort the whole walk, so it's logged and skipped like any
+				// other per-file error.
+				resolved, err := os.Stat(path)
+				if err != nil {
+					if p.config.Logging.Enabled {
+						log.Printf("Warning: skipping broken symlink %s: %v", path, err)
+					}
+					return nil
+				}
+				if resolved.IsDir() {
+					return nil
+				}
+				info = resolved
+			}
+
+			if info.IsDir() {
+				return nil
+			}
+			select {
+			case out <- walkEntry{path: path, info: info}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	return out
+}
+
 // cleanEmptyDirs removes empty directories in the source tree
 func (p *Processor) cleanEmptyDirs() error {
 	var dirsToCheck []string
@@ -173,54 +386,68 @@ func (p *Processor) cleanEmptyDirs() error {
 	return nil
 }
 
-// GetStats returns the current processing statistics
-func (p *Processor) GetStats() Stats {
-	return p.stats
+// GetStats returns the processing statistics gathered so far. The result
+// is a live pointer: its counters are safe to read while the pipeline is
+// still running, but may keep changing until Process returns.
+func (p *Processor) GetStats() *Stats {
+	return &p.stats
 }
 
-func (p *Processor) processFile(path string, info os.FileInfo, err error) error {
-	if err != nil {
-		return err
+// parseWorker reads walked files from paths, resolves each into a job, and
+// sends it to jobs. Files that don't match a configured media type are
+// silently dropped; errors are reported on errs so a bad file doesn't stop
+// the rest of the pipeline.
+func (p *Processor) parseWorker(ctx context.Context, paths <-chan walkEntry, jobs chan<- job, errs chan<- error) {
+	for entry := range paths {
+		j, skip, err := p.parseEntry(ctx, entry.path, entry.info)
+		if err != nil {
+			errs <- err
+			continue
+		}
+		if skip {
+			continue
+		}
+		select {
+		case jobs <- j:
+		case <-ctx.Done():
+			return
+		}
 	}
+}
 
-	// Skip directories
-	if info.IsDir() {
-		return nil
+// parseEntry resolves one walked file into a job: it checks the media
+// type, records size/EXIF stats, computes the content hash (when
+// content-addressed mode is enabled), and renders the folder template into
+// a destination path.
+func (p *Processor) parseEntry(ctx context.Context, path string, info os.FileInfo) (j job, skip bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return job{}, false, err
 	}
 
-	// Check if file is a supported media type
 	ext := strings.ToLower(filepath.Ext(path))
 	mediaType := p.getMediaType(ext)
 	if mediaType == "" {
-		return nil
+		return job{}, true, nil
 	}
 
-	// Track the directory containing this file
-	p.processedDirs[filepath.Dir(path)] = true
-
-	// Update media type counts
-	p.stats.ProcessedFiles++
+	p.stats.ProcessedFiles.Add(1)
 	if mediaType == "images" {
-		p.stats.ImageCount++
+		p.stats.ImageCount.Add(1)
 	} else if mediaType == "videos" {
-		p.stats.VideoCount++
+		p.stats.VideoCount.Add(1)
 	}
+	p.stats.TotalSize.Add(info.Size())
 
-	// Update total size
-	p.stats.TotalSize += info.Size()
-
-	// Get file date
-	date, err := p.getFileDate(path)
+	date, err := p.getFileDate(ctx, path)
 	if err != nil {
 		if p.config.Logging.Enabled {
 			log.Printf("Warning: Could not get EXIF data for %s, using file modification time", path)
 		}
 		date = info.ModTime()
 	} else {
-		p.stats.ExifFound++
+		p.stats.ExifFound.Add(1)
 	}
 
-	// Create file info for template
 	fileInfo := FileInfo{
 		Year:      date.Format("2006"),
 		Month:     date.Format("01"),
@@ -231,21 +458,33 @@ func (p *Processor) processFile(path string, info os.FileInfo, err error) error
 		Extension: ext[1:], // Remove the dot
 	}
 
+	// In content-addressed mode the hash drives both the canonical
+	// storage path and (optionally) the folder template, so it must be
+	// known before the template is rendered.
+	var hash string
+	if p.config.ContentAddressed.Enabled {
+		hash, err = p.calculateFileHash(ctx, path)
+		if err != nil {
+			return job{}, false, fmt.Errorf("failed to hash file %s: %v", path, err)
+		}
+		fileInfo.Hash = hash
+	}
+
 	// Parse and execute the template
 	tmpl, err := template.New("folder").Parse(p.config.FolderTemplate)
 	if err != nil {
-		return fmt.Errorf("failed to parse template: %v", err)
+		return job{}, false, fmt.Errorf("failed to parse template: %v", err)
 	}
 
 	var targetPath strings.Builder
 	if err := tmpl.Execute(&targetPath, fileInfo); err != nil {
-		return fmt.Errorf("failed to execute template: %v", err)
+		return job{}, false, fmt.Errorf("failed to execute template: %v", err)
 	}
 
 	// Create the target directory
 	newDir := filepath.Join(p.targetDir, targetPath.String())
 	if err := os.MkdirAll(newDir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory %s: %v", newDir, err)
+		return job{}, false, fmt.Errorf("failed to create directory %s: %v", newDir, err)
 	}
 
 	// Generate target filename
@@ -260,55 +499,162 @@ func (p *Processor) processFile(path string, info os.FileInfo, err error) error
 			ext)
 	}
 
-	// Copy file to new location
-	newPath := filepath.Join(newDir, filename)
+	p.filesMu.Lock()
+	p.processedDirs[filepath.Dir(path)] = true
+	p.filesMu.Unlock()
+
+	return job{
+		srcPath:  path,
+		srcSize:  info.Size(),
+		destPath: filepath.Join(newDir, filename),
+		ext:      ext,
+		hash:     hash,
+	}, false, nil
+}
 
-	// Check if target file exists and is identical
-	if identical, err := p.areFilesIdentical(path, newPath); err != nil {
-		if !os.IsNotExist(err) {
+// copyWorker reads parsed jobs and places each one in the target
+// directory, reporting any failure on errs.
+func (p *Processor) copyWorker(ctx context.Context, jobs <-chan job, errs chan<- error) {
+	for j := range jobs {
+		if err := p.executeJob(ctx, j); err != nil {
+			errs <- err
+		}
+	}
+}
+
+// executeJob copies (or, in content-addressed mode, stores and links) one
+// parsed job into place, then removes the source file if requested.
+func (p *Processor) executeJob(ctx context.Context, j job) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if p.config.ContentAddressed.Enabled {
+		if err := p.storeContentAddressed(ctx, j.srcPath, j.destPath, j.hash, j.ext, j.srcSize); err != nil {
+			return fmt.Errorf("failed to store %s in content-addressed layout: %v", j.srcPath, err)
+		}
+	} else {
+		// Serialize everything from here through the eventual write so that
+		// two copy workers racing on the same destination path can't both
+		// observe it as free and clobber each other (e.g. the "suffix"
+		// archiver picking the same candidate twice).
+		unlock := p.destLocks.Lock(j.destPath)
+		defer unlock()
+
+		// Check if target file exists and is identical
+		identical, err := p.areFilesIdentical(ctx, j.srcPath, j.destPath)
+		if err != nil && !os.IsNotExist(err) {
 			return fmt.Errorf("error comparing files: %v", err)
 		}
-	} else if identical {
-		if p.config.Logging.Enabled {
-			log.Printf("Skipping identical file: %s", path)
+		if identical {
+			if p.config.Logging.Enabled {
+				log.Printf("Skipping identical file: %s", j.srcPath)
+			}
+			if p.deleteSource {
+				if err := os.Remove(j.srcPath); err != nil {
+					return fmt.Errorf("failed to delete source file %s: %v", j.srcPath, err)
+				}
+			}
+			return nil
 		}
-		if p.deleteSource {
-			if err := os.Remove(path); err != nil {
-				return fmt.Errorf("failed to delete source file %s: %v", path, err)
+
+		if !os.IsNotExist(err) {
+			// A different file already occupies destPath: consult the archiver.
+			skip, resolvedPath, err := p.resolveCollision(j.destPath)
+			if err != nil {
+				return err
+			}
+			if skip {
+				if p.config.Logging.Enabled {
+					log.Printf("Skipping %s: %s already exists with different content", j.srcPath, j.destPath)
+				}
+				if p.deleteSource {
+					if err := os.Remove(j.srcPath); err != nil {
+						return fmt.Errorf("failed to delete source file %s: %v", j.srcPath, err)
+					}
+				}
+				return nil
 			}
+			j.destPath = resolvedPath
 		}
-		return nil
-	}
 
-	if err := p.copyFile(path, newPath); err != nil {
-		return fmt.Errorf("failed to copy file %s: %v", path, err)
+		if err := p.copyFile(ctx, j.srcPath, j.destPath); err != nil {
+			return fmt.Errorf("failed to copy file %s: %v", j.srcPath, err)
+		}
 	}
 
 	// Delete source file if requested
 	if p.deleteSource {
-		if err := os.Remove(path); err != nil {
-			return fmt.Errorf("failed to delete source file %s: %v", path, err)
+		if err := os.Remove(j.srcPath); err != nil {
+			return fmt.Errorf("failed to delete source file %s: %v", j.srcPath, err)
 		}
 		if p.config.Logging.Enabled {
-			log.Printf("Moved %s to %s", path, newPath)
+			log.Printf("Moved %s to %s", j.srcPath, j.destPath)
+		}
+	} else if p.config.Logging.Enabled {
+		log.Printf("Copied %s to %s", j.srcPath, j.destPath)
+	}
+
+	p.filesMu.Lock()
+	p.processedFiles = append(p.processedFiles, j.destPath)
+	p.filesMu.Unlock()
+
+	return nil
+}
+
+// resolveCollision asks p.archiver what to do about a different file
+// already occupying destPath: whether to archive it out of the way first,
+// and where the incoming file should ultimately be written. skip is true
+// when the incoming file should be dropped entirely (the "skip" strategy).
+func (p *Processor) resolveCollision(destPath string) (skip bool, resolvedPath string, err error) {
+	existingInfo, err := os.Stat(destPath)
+	if err != nil {
+		return false, "", fmt.Errorf("error inspecting existing file %s: %v", destPath, err)
+	}
+
+	if p.archiver.ShouldArchiveNow(destPath, existingInfo) {
+		archivePath, err := p.archiver.NextLogFilePath(destPath)
+		if err != nil {
+			return false, "", fmt.Errorf("error resolving archive path for %s: %v", destPath, err)
+		}
+		if err := p.archiver.HookBeforeArchive(destPath, archivePath); err != nil {
+			return false, "", fmt.Errorf("archive hook failed for %s: %v", destPath, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+			return false, "", fmt.Errorf("failed to create archive directory for %s: %v", archivePath, err)
+		}
+		if err := os.Rename(destPath, archivePath); err != nil {
+			return false, "", fmt.Errorf("failed to archive %s to %s: %v", destPath, archivePath, err)
+		}
+		if err := p.archiver.HookAfterArchive(destPath, archivePath); err != nil {
+			return false, "", fmt.Errorf("archive hook failed for %s: %v", destPath, err)
 		}
-		// Add to processed files list
-		p.processedFiles = append(p.processedFiles, newPath)
-	} else {
 		if p.config.Logging.Enabled {
-			log.Printf("Copied %s to %s", path, newPath)
+			log.Printf("Archived %s to %s", destPath, archivePath)
 		}
-		// Add to processed files list
-		p.processedFiles = append(p.processedFiles, newPath)
 	}
 
-	return nil
+	incoming, err := p.archiver.IncomingPath(destPath)
+	if err != nil {
+		return false, "", fmt.Errorf("error resolving incoming path for %s: %v", destPath, err)
+	}
+
+	return incoming == "", incoming, nil
 }
 
-// areFilesIdentical efficiently compares two files by size and hash
-func (p *Processor) areFilesIdentical(src, dst string) (bool, error) {
-	// First check if destination exists
-	dstInfo, err := os.Stat(dst)
+// areFilesIdentical efficiently compares two files by size and hash.
+// When content-addressed mode is enabled, dst is usually a hardlink or
+// symlink back to src (the canonical content-addressed copy); in that
+// common case a single stat confirms identity without reading either
+// file's content.
+func (p *Processor) areFilesIdentical(ctx context.Context, src, dst string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	// First check if destination exists, without following a symlink so we
+	// can recognize the content-addressed case below.
+	dstInfo, err := os.Lstat(dst)
 	if err != nil {
 		return false, err
 	}
@@ -319,18 +665,34 @@ func (p *Processor) areFilesIdentical(src, dst string) (bool, error) {
 		return false, err
 	}
 
+	if p.config.ContentAddressed.Enabled {
+		if dstInfo.Mode()&os.ModeSymlink != 0 {
+			if target, err := os.Readlink(dst); err == nil && target == src {
+				return true, nil
+			}
+		} else if os.SameFile(srcInfo, dstInfo) {
+			return true, nil
+		}
+	}
+
+	// Resolve dst through a symlink (if any) for the size/hash comparison below.
+	resolvedDstInfo, err := os.Stat(dst)
+	if err != nil {
+		return false, err
+	}
+
 	// Quick size comparison
-	if srcInfo.Size() != dstInfo.Size() {
+	if srcInfo.Size() != resolvedDstInfo.Size() {
 		return false, nil
 	}
 
 	// Compare file contents using SHA-256 hash
-	srcHash, err := p.calculateFileHash(src)
+	srcHash, err := p.calculateFileHash(ctx, src)
 	if err != nil {
 		return false, err
 	}
 
-	dstHash, err := p.calculateFileHash(dst)
+	dstHash, err := p.calculateFileHash(ctx, dst)
 	if err != nil {
 		return false, err
 	}
@@ -338,8 +700,128 @@ func (p *Processor) areFilesIdentical(src, dst string) (bool, error) {
 	return srcHash == dstHash, nil
 }
 
+// contentFilePath returns the canonical content-addressed path for a file
+// with the given hash and extension: content/<hh>/<hash><ext>.
+func (p *Processor) contentFilePath(hash, ext string) string {
+	return filepath.Join(p.targetDir, contentDirName, hash[:2], hash+ext)
+}
+
+// ensureContentShards pre-creates the 256 shard directories
+// (content/00 .. content/ff) used by the content-addressed store, so that
+// storeContentAddressed never has to MkdirAll on its hot path.
+func (p *Processor) ensureContentShards() error {
+	p.contentShardsMu.Lock()
+	defer p.contentShardsMu.Unlock()
+
+	if p.contentShardsDone {
+		return nil
+	}
+
+	const hexDigits = "0123456789abcdef"
+	for _, hi := range hexDigits {
+		for _, lo := range hexDigits {
+			shard := filepath.Join(p.targetDir, contentDirName, string(hi)+string(lo))
+			if err := os.MkdirAll(shard, 0755); err != nil {
+				return fmt.Errorf("failed to create content shard %s: %v", shard, err)
+			}
+		}
+	}
+
+	p.contentShardsDone = true
+	return nil
+}
+
+// storeContentAddressed copies src into the content-addressed store exactly
+// once per hash, then links newPath to that canonical copy, preferring a
+// hardlink and falling back to a symlink when the target filesystem
+// doesn't support hardlinks (e.g. newPath is on a different device).
+func (p *Processor) storeContentAddressed(ctx context.Context, src, newPath, hash, ext string, size int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := p.ensureContentShards(); err != nil {
+		return err
+	}
+
+	contentPath := p.contentFilePath(hash, ext)
+
+	// Serialize the check-and-create of the canonical copy, keyed on
+	// contentPath, so two workers hashing to the same content never both
+	// observe it missing (or catch it mid-write) and race to create it.
+	unlockContent := p.destLocks.Lock(contentPath)
+	if _, err := os.Stat(contentPath); err == nil {
+		p.stats.DuplicatesSkipped.Add(1)
+		p.stats.BytesDeduplicated.Add(size)
+	} else if os.IsNotExist(err) {
+		if err := p.copyFile(ctx, src, contentPath); err != nil {
+			unlockContent()
+			return fmt.Errorf("failed to write canonical copy %s: %v", contentPath, err)
+		}
+	} else {
+		unlockContent()
+		return err
+	}
+	unlockContent()
+
+	// Serialize everything from here through the eventual link so that two
+	// copy workers racing on the same destination path can't both observe
+	// it as free and clobber each other.
+	unlock := p.destLocks.Lock(newPath)
+	defer unlock()
+
+	identical, err := p.areFilesIdentical(ctx, contentPath, newPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error comparing %s to canonical copy: %v", newPath, err)
+	}
+	if identical {
+		if p.config.Logging.Enabled {
+			log.Printf("Already linked: %s", newPath)
+		}
+		return nil
+	}
+	if err == nil {
+		// A different file occupies newPath: consult the archiver, same as
+		// the non-content-addressed path does.
+		skip, resolvedPath, err := p.resolveCollision(newPath)
+		if err != nil {
+			return err
+		}
+		if skip {
+			if p.config.Logging.Enabled {
+				log.Printf("Skipping %s: %s already exists with different content", src, newPath)
+			}
+			return nil
+		}
+		newPath = resolvedPath
+
+		// Unlike copyFile (which truncates via os.Create), os.Link requires
+		// newPath to not exist yet. If the archiver didn't move anything
+		// out of the way (e.g. "overwrite", or "suffix" resolving back to a
+		// path that's still occupied), clear it ourselves.
+		if _, err := os.Lstat(newPath); err == nil {
+			if err := os.Remove(newPath); err != nil {
+				return fmt.Errorf("failed to replace existing file %s: %v", newPath, err)
+			}
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("error inspecting %s: %v", newPath, err)
+		}
+	}
+
+	if err := os.Link(contentPath, newPath); err != nil {
+		if err := os.Symlink(contentPath, newPath); err != nil {
+			return fmt.Errorf("failed to link %s to %s: %v", newPath, contentPath, err)
+		}
+	}
+
+	if p.config.Logging.Enabled {
+		log.Printf("Linked %s to canonical copy %s", newPath, contentPath)
+	}
+	return nil
+}
+
 // calculateFileHash calculates SHA-256 hash of a file using buffered reads
-func (p *Processor) calculateFileHash(path string) (string, error) {
+func (p *Processor) calculateFileHash(ctx context.Context, path string) (string, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return "", err
@@ -348,9 +830,10 @@ func (p *Processor) calculateFileHash(path string) (string, error) {
 
 	hash := sha256.New()
 	buf := make([]byte, copyBufferSize)
+	reader := &ctxReader{ctx: ctx, r: file}
 
 	for {
-		n, err := file.Read(buf)
+		n, err := reader.Read(buf)
 		if n > 0 {
 			hash.Write(buf[:n])
 		}
@@ -376,43 +859,21 @@ func (p *Processor) getMediaType(ext string) string {
 	return ""
 }
 
-// checkExiftool verifies that exiftool is available on the system
-func (p *Processor) checkExiftool() error {
-	cmd := exec.Command("exiftool", "-ver")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("exiftool is not available: %v\nPlease install exiftool to extract EXIF data from media files", err)
-	}
-	return nil
-}
-
-func (p *Processor) getFileDate(path string) (time.Time, error) {
-	// Check exiftool availability on first call
-	if !p.exiftoolChecked {
-		p.exiftoolChecked = true
-		if err := p.checkExiftool(); err != nil {
-			return time.Time{}, err
-		}
-		p.exiftoolAvailable = true
-	}
-
-	// Try to get DateTimeOriginal first, then DateTime as fallback
-	cmd := exec.Command("exiftool", "-DateTimeOriginal", "-DateTime", "-s", "-s", "-s", path)
-	output, err := cmd.Output()
-	if err != nil {
-		return time.Time{}, fmt.Errorf("exiftool execution failed: %v", err)
-	}
+// getFileDate resolves path's capture date via the DateExtractor registered
+// for its extension, falling back to the generic exiftool extractor for
+// formats with no dedicated reader.
+func (p *Processor) getFileDate(ctx context.Context, path string) (time.Time, error) {
+	ext := strings.ToLower(filepath.Ext(path))
 
-	// Parse the output - exiftool returns the first matching tag
-	dateTimeStr := strings.TrimSpace(string(output))
-	if dateTimeStr == "" {
-		return time.Time{}, fmt.Errorf("no DateTime found in EXIF")
+	extractor, ok := p.dateExtractors[ext]
+	if !ok {
+		extractor = p.fallbackExtractor
 	}
 
-	// Parse the EXIF date format: "YYYY:MM:DD HH:MM:SS"
-	return time.Parse("2006:01:02 15:04:05", dateTimeStr)
+	return extractor.ExtractDate(ctx, path)
 }
 
-func (p *Processor) copyFile(src, dst string) error {
+func (p *Processor) copyFile(ctx context.Context, src, dst string) error {
 	srcFile, err := os.Open(src)
 	if err != nil {
 		return err
@@ -426,7 +887,7 @@ func (p *Processor) copyFile(src, dst string) error {
 	defer dstFile.Close()
 
 	buf := make([]byte, copyBufferSize)
-	_, err = io.CopyBuffer(dstFile, srcFile, buf)
+	_, err = io.CopyBuffer(dstFile, &ctxReader{ctx: ctx, r: srcFile}, buf)
 	if err != nil {
 		return err
 	}
@@ -436,6 +897,39 @@ func (p *Processor) copyFile(src, dst string) error {
 	if err != nil {
 		return err
 	}
+	if err := os.Chmod(dst, srcInfo.Mode()); err != nil {
+		return err
+	}
+
+	if p.config.PreserveTimes {
+		atime, mtime := fileTimes(srcInfo)
+		if err := os.Chtimes(dst, atime, mtime); err != nil {
+			return fmt.Errorf("failed to preserve timestamps on %s: %v", dst, err)
+		}
+	}
+
+	if p.config.PreserveOwnership {
+		if uid, gid, ok := fileOwner(srcInfo); ok {
+			if err := os.Chown(dst, uid, gid); err != nil {
+				return fmt.Errorf("failed to preserve ownership on %s: %v", dst, err)
+			}
+		}
+	}
 
-	return os.Chmod(dst, srcInfo.Mode())
+	return nil
+}
+
+// ctxReader wraps an io.Reader so that long-running reads (file hashing,
+// file copies) notice context cancellation between chunks instead of
+// running to completion regardless of ctx.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *ctxReader) Read(buf []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(buf)
 }
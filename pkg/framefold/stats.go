@@ -3,34 +3,60 @@ package framefold
 import (
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 	"time"
 )
 
-// Stats tracks processing statistics
+// Stats tracks processing statistics. The counters are atomic.Int64 so
+// that the parse and copy worker pools can update them concurrently
+// without a separate lock.
 type Stats struct {
-	ImageCount     int64     `json:"images"`
-	VideoCount     int64     `json:"videos"`
-	ExifFound      int64     `json:"files_with_exif"`
-	TotalSize      int64     `json:"total_size_bytes"`
-	ProcessedFiles int64     `json:"total_files"`
-	StartTime      time.Time `json:"-"` // Don't include in JSON output
-	Duration      string    `json:"duration"`
-	HumanSize     string    `json:"total_size"`
+	ImageCount        atomic.Int64
+	VideoCount        atomic.Int64
+	ExifFound         atomic.Int64
+	TotalSize         atomic.Int64
+	ProcessedFiles    atomic.Int64
+	DuplicatesSkipped atomic.Int64
+	BytesDeduplicated atomic.Int64
+	StartTime         time.Time // Set once before the pipeline starts; read-only afterward
+}
+
+// statsSnapshot is the plain-value shape Stats is rendered as. atomic.Int64
+// has unexported fields and can't be marshaled directly.
+type statsSnapshot struct {
+	ImageCount        int64  `json:"images"`
+	VideoCount        int64  `json:"videos"`
+	ExifFound         int64  `json:"files_with_exif"`
+	TotalSize         int64  `json:"total_size_bytes"`
+	ProcessedFiles    int64  `json:"total_files"`
+	DuplicatesSkipped int64  `json:"duplicates_skipped"`
+	BytesDeduplicated int64  `json:"bytes_deduplicated"`
+	Duration          string `json:"duration"`
+	HumanSize         string `json:"total_size"`
 }
 
 // String formats the stats as JSON
-func (s Stats) String() string {
+func (s *Stats) String() string {
 	// Calculate duration
 	duration := time.Since(s.StartTime)
 	minutes := int(duration.Minutes())
 	seconds := int(duration.Seconds()) % 60
-	s.Duration = fmt.Sprintf("%d minutes %d seconds", minutes, seconds)
-	
-	// Format human-readable size
-	s.HumanSize = formatSize(s.TotalSize)
+
+	totalSize := s.TotalSize.Load()
+	snapshot := statsSnapshot{
+		ImageCount:        s.ImageCount.Load(),
+		VideoCount:        s.VideoCount.Load(),
+		ExifFound:         s.ExifFound.Load(),
+		TotalSize:         totalSize,
+		ProcessedFiles:    s.ProcessedFiles.Load(),
+		DuplicatesSkipped: s.DuplicatesSkipped.Load(),
+		BytesDeduplicated: s.BytesDeduplicated.Load(),
+		Duration:          fmt.Sprintf("%d minutes %d seconds", minutes, seconds),
+		HumanSize:         formatSize(totalSize),
+	}
 
 	// Marshal to JSON
-	data, err := json.MarshalIndent(s, "", "  ")
+	data, err := json.MarshalIndent(snapshot, "", "  ")
 	if err != nil {
 		return fmt.Sprintf("error formatting stats: %v", err)
 	}